@@ -0,0 +1,144 @@
+package flutter
+
+import (
+	"github.com/go-flutter-desktop/go-flutter/plugin"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+const keyboardLayoutChannelName = "flutter/keyboard_layout"
+
+// KeyboardShortcuts holds the glfw.Key values that, on the current keyboard
+// layout, produce the letters used for the SelectAll/Copy/Cut/Paste
+// shortcuts (a/c/x/v). Physical scancodes differ between AZERTY, QWERTY,
+// Dvorak, etc., so these must be resolved per layout rather than hardcoded.
+type KeyboardShortcuts struct {
+	SelectAll glfw.Key
+	Copy      glfw.Key
+	Cut       glfw.Key
+	Paste     glfw.Key
+	name      string
+}
+
+// namedKeyboardShortcuts are well-known physical key layouts that can be
+// forced through OptionKeyboardLayout, bypassing GLFW layout detection
+// entirely (useful on platforms where glfw.GetKeyName is unreliable).
+var namedKeyboardShortcuts = map[string]KeyboardShortcuts{
+	// us (QWERTY) is also the fallback layout used when detection fails.
+	"us": {SelectAll: glfw.KeyA, Copy: glfw.KeyC, Cut: glfw.KeyX, Paste: glfw.KeyV, name: "us"},
+	// de (QWERTZ) only swaps y/z relative to QWERTY, so a/c/x/v are unaffected.
+	"de": {SelectAll: glfw.KeyA, Copy: glfw.KeyC, Cut: glfw.KeyX, Paste: glfw.KeyV, name: "de"},
+	// fr (AZERTY) swaps a/q and w/z; SelectAll moves to the physical Q key.
+	"fr": {SelectAll: glfw.KeyQ, Copy: glfw.KeyC, Cut: glfw.KeyX, Paste: glfw.KeyV, name: "fr"},
+}
+
+// namedKeyboardShortcutsOrder fixes the lookup order for layoutName. Several
+// named layouts share the same shortcut keys (e.g. "us" and "de" both use
+// a/c/x/v), so iterating namedKeyboardShortcuts directly would make the
+// reported layout name depend on Go's randomized map iteration order.
+var namedKeyboardShortcutsOrder = []string{"us", "de", "fr"}
+
+// KeyboardLayoutDetector resolves the KeyboardShortcuts for the keyboard
+// layout that is currently active in the OS.
+type KeyboardLayoutDetector struct {
+	forcedLayout string
+}
+
+// DetectLayout returns the KeyboardShortcuts for the active layout. When a
+// layout named in namedKeyboardShortcuts was forced through
+// OptionKeyboardLayout, its predefined shortcuts are returned; otherwise
+// (including a forced name that is not in namedKeyboardShortcuts) the layout
+// is detected through glfw.GetKeyName.
+func (d *KeyboardLayoutDetector) DetectLayout() KeyboardShortcuts {
+	if forced, ok := namedKeyboardShortcuts[d.forcedLayout]; ok {
+		return forced
+	}
+
+	shortcuts := KeyboardShortcuts{
+		SelectAll: keyProducingLetter('a'),
+		Copy:      keyProducingLetter('c'),
+		Cut:       keyProducingLetter('x'),
+		Paste:     keyProducingLetter('v'),
+	}
+	if shortcuts.SelectAll == glfw.KeyUnknown || shortcuts.Copy == glfw.KeyUnknown ||
+		shortcuts.Cut == glfw.KeyUnknown || shortcuts.Paste == glfw.KeyUnknown {
+		// glfw.GetKeyName failed to resolve at least one of the four letters
+		// (e.g. headless, some Wayland/X setups); fall back to "us" rather
+		// than leaving SelectAll/Copy/Cut/Paste bound to KeyUnknown.
+		return namedKeyboardShortcuts["us"]
+	}
+	shortcuts.name = layoutName(shortcuts)
+	return shortcuts
+}
+
+// keyProducingLetter returns the glfw.Key that, on the currently active OS
+// keyboard layout, produces letter. It falls back to glfw.KeyUnknown if no
+// key produces it (e.g. non-Latin layouts).
+func keyProducingLetter(letter rune) glfw.Key {
+	for key := glfw.KeyA; key <= glfw.KeyZ; key++ {
+		name := glfw.GetKeyName(key, 0)
+		if len(name) == 1 && rune(name[0]) == letter {
+			return key
+		}
+	}
+	return glfw.KeyUnknown
+}
+
+// layoutName does a best-effort match of the detected shortcuts against
+// namedKeyboardShortcuts, falling back to "us" when nothing matches. Ties
+// (layouts with identical shortcut keys) resolve deterministically to
+// whichever name comes first in namedKeyboardShortcutsOrder.
+func layoutName(shortcuts KeyboardShortcuts) string {
+	for _, name := range namedKeyboardShortcutsOrder {
+		known := namedKeyboardShortcuts[name]
+		if known.SelectAll == shortcuts.SelectAll && known.Copy == shortcuts.Copy &&
+			known.Cut == shortcuts.Cut && known.Paste == shortcuts.Paste {
+			return name
+		}
+	}
+	return "us"
+}
+
+// OptionKeyboardLayout forces the given named keyboard layout (e.g. "us",
+// "de", "fr") instead of detecting it from the OS, overriding
+// KeyboardLayoutDetector. A name that is not in namedKeyboardShortcuts has
+// no effect on the shortcuts, which are then detected as usual.
+func OptionKeyboardLayout(name string) Option {
+	return func(c *config) {
+		c.keyboardLayoutName = name
+		defaultKeyboardLayoutPlugin.detector.forcedLayout = name
+	}
+}
+
+// keyboardLayoutPlugin implements flutter.Plugin and exposes the detected
+// keyboard layout to Dart code over the flutter/keyboard_layout
+// EventChannel. defaultTextinputPlugin shares this plugin's detector so the
+// SelectAll/Copy/Cut/Paste shortcuts resolve against the same layout.
+type keyboardLayoutPlugin struct {
+	messenger plugin.BinaryMessenger
+	channel   *plugin.EventChannel
+
+	detector KeyboardLayoutDetector
+}
+
+var defaultKeyboardLayoutPlugin = &keyboardLayoutPlugin{}
+
+var _ Plugin = &keyboardLayoutPlugin{} // compile-time type check
+
+func (p *keyboardLayoutPlugin) InitPlugin(messenger plugin.BinaryMessenger) error {
+	p.messenger = messenger
+	p.channel = plugin.NewEventChannel(p.messenger, keyboardLayoutChannelName, plugin.JSONMethodCodec{})
+	p.channel.Handle(p)
+
+	return nil
+}
+
+// OnListen reports the layout active at subscription time. GLFW 3.2 has no
+// callback for OS-level keyboard-layout switches, so a layout change while
+// the app is running is only picked up the next time Dart re-subscribes.
+func (p *keyboardLayoutPlugin) OnListen(arguments interface{}, sink plugin.EventSink) {
+	sink.Success(p.detector.DetectLayout().name)
+}
+
+func (p *keyboardLayoutPlugin) OnCancel(arguments interface{}) {}
+
+var _ plugin.StreamHandler = &keyboardLayoutPlugin{} // compile-time type check
@@ -0,0 +1,36 @@
+package flutter
+
+import (
+	"testing"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// TestLayoutNameIsDeterministic guards against layoutName depending on Go's
+// randomized map iteration order: "us" and "de" share identical shortcut
+// keys, so a QWERTY/QWERTZ match must always resolve to the same name.
+func TestLayoutNameIsDeterministic(t *testing.T) {
+	qwerty := KeyboardShortcuts{SelectAll: glfw.KeyA, Copy: glfw.KeyC, Cut: glfw.KeyX, Paste: glfw.KeyV}
+
+	for i := 0; i < 50; i++ {
+		if got := layoutName(qwerty); got != "us" {
+			t.Fatalf("layoutName(qwerty) = %q, want %q", got, "us")
+		}
+	}
+}
+
+func TestLayoutNameMatchesAzerty(t *testing.T) {
+	azerty := KeyboardShortcuts{SelectAll: glfw.KeyQ, Copy: glfw.KeyC, Cut: glfw.KeyX, Paste: glfw.KeyV}
+
+	if got := layoutName(azerty); got != "fr" {
+		t.Fatalf("layoutName(azerty) = %q, want %q", got, "fr")
+	}
+}
+
+func TestLayoutNameFallsBackToUs(t *testing.T) {
+	unknown := KeyboardShortcuts{SelectAll: glfw.KeyUnknown, Copy: glfw.KeyUnknown, Cut: glfw.KeyUnknown, Paste: glfw.KeyUnknown}
+
+	if got := layoutName(unknown); got != "us" {
+		t.Fatalf("layoutName(unknown) = %q, want %q", got, "us")
+	}
+}
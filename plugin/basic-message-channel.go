@@ -0,0 +1,112 @@
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// MessageHandler defines the interface for a message handler.
+type MessageHandler interface {
+	// HandleMessage is called whenever an incoming message is received on
+	// the channel it is registered on.
+	HandleMessage(message interface{}) (reply interface{}, err error)
+}
+
+// The MessageHandlerFunc type is an adapter to allow the use of ordinary
+// functions as message handlers. If f is a function with the appropriate
+// signature, MessageHandlerFunc(f) is a MessageHandler that calls f.
+type MessageHandlerFunc func(message interface{}) (reply interface{}, err error)
+
+// HandleMessage calls f(message).
+func (f MessageHandlerFunc) HandleMessage(message interface{}) (reply interface{}, err error) {
+	return f(message)
+}
+
+// BasicMessageChannel provides a bidirectional communication channel using
+// an arbitrary codec, exchanging a single message per invocation. It must be
+// used with a codec, for example the StandardMessageCodec. For more
+// information please read
+// https://flutter.dev/docs/development/platform-integration/platform-channels
+type BasicMessageChannel struct {
+	messenger    BinaryMessenger
+	channelName  string
+	messageCodec MessageCodec
+
+	handler MessageHandler
+}
+
+// NewBasicMessageChannel creates a new basic message channel.
+func NewBasicMessageChannel(messenger BinaryMessenger, channelName string, messageCodec MessageCodec) (channel *BasicMessageChannel) {
+	m := &BasicMessageChannel{
+		messenger:    messenger,
+		channelName:  channelName,
+		messageCodec: messageCodec,
+	}
+	messenger.SetChannelHandler(channelName, m.handleChannel)
+	return m
+}
+
+// Send sends the given message to the Flutter application and waits for its
+// reply.
+func (m *BasicMessageChannel) Send(message interface{}) (reply interface{}, err error) {
+	encodedMessage, err := m.messageCodec.EncodeMessage(message)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode message")
+	}
+	encodedReply, err := m.messenger.Send(m.channelName, encodedMessage)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send message")
+	}
+	reply, err = m.messageCodec.DecodeMessage(encodedReply)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode reply")
+	}
+	return reply, nil
+}
+
+// SetMessageHandler registers a message handler on this channel for
+// receiving messages sent from the Flutter application.
+//
+// Consecutive calls override any existing handler registration. When given
+// nil as handler, the previously registered handler is unregistered.
+//
+// When no handler is registered, incoming messages receive a nil reply (null
+// on the dart side) and a warning is logged to the console.
+func (m *BasicMessageChannel) SetMessageHandler(handler MessageHandler) {
+	m.handler = handler
+}
+
+// handleChannel decodes an incoming binary message, calls the handler, and
+// encodes the outgoing reply.
+func (m *BasicMessageChannel) handleChannel(binaryMessage []byte) (binaryReply []byte, err error) {
+	message, err := m.messageCodec.DecodeMessage(binaryMessage)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode incomming message")
+	}
+
+	if m.handler == nil {
+		fmt.Printf("go-flutter: no message handler registered on channel '%s'\n", m.channelName)
+		return nil, nil
+	}
+
+	defer func() {
+		p := recover()
+		if p != nil {
+			fmt.Printf("go-flutter: recovered from panic while handling message on channel '%s': %v", m.channelName, p)
+		}
+	}()
+	reply, err := m.handler.HandleMessage(message)
+	if err != nil {
+		fmt.Printf("go-flutter: handler for channel '%s' returned an error: %v\n", m.channelName, err)
+		return nil, nil
+	}
+
+	binaryReply, err = m.messageCodec.EncodeMessage(reply)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode reply")
+	}
+	return binaryReply, nil
+}
+
+var _ ChannelHandlerFunc = (*BasicMessageChannel)(nil).handleChannel // compile-time type check
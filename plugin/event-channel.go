@@ -0,0 +1,160 @@
+package plugin
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// EventSink is handed to a StreamHandler's OnListen implementation and is
+// used to push events (or termination) to the Flutter side for as long as
+// the corresponding subscription is active.
+type EventSink interface {
+	// Success sends a successful event.
+	Success(event interface{})
+
+	// Error sends an error event. The subscription remains active
+	// afterwards, mirroring Flutter's EventChannel semantics.
+	Error(code string, message string, details interface{})
+
+	// EndOfStream signals that no more events will be sent and the
+	// subscription is complete.
+	EndOfStream()
+}
+
+// StreamHandler handles stream setup and teardown requests coming from the
+// Flutter application through an EventChannel.
+type StreamHandler interface {
+	// OnListen is called when the Flutter side starts listening, i.e. when
+	// StreamController.stream.listen() is called. sink can be used to push
+	// events for as long as the subscription is active.
+	OnListen(arguments interface{}, sink EventSink)
+
+	// OnCancel is called when the Flutter side cancels its subscription.
+	OnCancel(arguments interface{})
+}
+
+// EventChannel is a named channel for communicating with the Flutter
+// application using event streams, as opposed to the single request/reply
+// exchanges of MethodChannel. It demuxes the "listen" and "cancel" method
+// calls Flutter's EventChannel sends and streams event envelopes back
+// through the BinaryMessenger. For more information please read
+// https://flutter.dev/docs/development/platform-integration/platform-channels
+type EventChannel struct {
+	messenger   BinaryMessenger
+	channelName string
+	methodCodec MethodCodec
+
+	handler StreamHandler
+	sink    *eventSink
+	sinkMu  sync.Mutex
+}
+
+// NewEventChannel creates a new event channel.
+func NewEventChannel(messenger BinaryMessenger, channelName string, methodCodec MethodCodec) (channel *EventChannel) {
+	e := &EventChannel{
+		messenger:   messenger,
+		channelName: channelName,
+		methodCodec: methodCodec,
+	}
+	messenger.SetChannelHandler(channelName, e.handleChannel)
+	return e
+}
+
+// Handle registers the StreamHandler responsible for this channel's
+// subscription lifecycle. Consecutive calls override any existing
+// registration; nil unregisters it.
+func (e *EventChannel) Handle(handler StreamHandler) {
+	e.handler = handler
+}
+
+func (e *EventChannel) handleChannel(binaryMessage []byte) (binaryReply []byte, err error) {
+	methodCall, err := e.methodCodec.DecodeMethodCall(binaryMessage)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode incomming message")
+	}
+
+	if e.handler == nil {
+		fmt.Printf("go-flutter: no stream handler registered on channel '%s'\n", e.channelName)
+		return nil, nil
+	}
+
+	switch methodCall.Method {
+	case "listen":
+		e.sinkMu.Lock()
+		previousSink := e.sink
+		e.sink = &eventSink{channel: e}
+		sink := e.sink
+		e.sinkMu.Unlock()
+		// A new listen while a subscription is already active replaces it
+		// without the Flutter side ever sending "cancel"; run the previous
+		// subscription's teardown so its OnCancel handler isn't skipped.
+		if previousSink != nil {
+			e.handler.OnCancel(nil)
+		}
+		e.handler.OnListen(methodCall.Arguments, sink)
+
+	case "cancel":
+		e.sinkMu.Lock()
+		e.sink = nil
+		e.sinkMu.Unlock()
+		e.handler.OnCancel(methodCall.Arguments)
+
+	default:
+		return nil, errors.Errorf("unsupported method '%s' on event channel '%s'", methodCall.Method, e.channelName)
+	}
+
+	binaryReply, err = e.methodCodec.EncodeSuccessEnvelope(nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode success envelope")
+	}
+	return binaryReply, nil
+}
+
+// eventSink implements EventSink, pushing event envelopes back over the
+// EventChannel's BinaryMessenger for as long as it is the channel's current
+// subscription.
+type eventSink struct {
+	channel *EventChannel
+}
+
+func (s *eventSink) send(envelope []byte, err error) {
+	if err != nil {
+		fmt.Printf("go-flutter: failed to encode event envelope on channel '%s': %v\n", s.channel.channelName, err)
+		return
+	}
+
+	s.channel.sinkMu.Lock()
+	active := s.channel.sink == s
+	s.channel.sinkMu.Unlock()
+	if !active {
+		return
+	}
+
+	_, err = s.channel.messenger.Send(s.channel.channelName, envelope)
+	if err != nil {
+		fmt.Printf("go-flutter: failed to send event on channel '%s': %v\n", s.channel.channelName, err)
+	}
+}
+
+func (s *eventSink) Success(event interface{}) {
+	s.send(s.channel.methodCodec.EncodeSuccessEnvelope(event))
+}
+
+func (s *eventSink) Error(code string, message string, details interface{}) {
+	s.send(s.channel.methodCodec.EncodeErrorEnvelope(code, message, details))
+}
+
+func (s *eventSink) EndOfStream() {
+	s.send(nil, nil)
+
+	s.channel.sinkMu.Lock()
+	if s.channel.sink == s {
+		s.channel.sink = nil
+	}
+	s.channel.sinkMu.Unlock()
+}
+
+var _ ChannelHandlerFunc = (*EventChannel)(nil).handleChannel // compile-time type check
+var _ EventSink = (*eventSink)(nil)                           // compile-time type check
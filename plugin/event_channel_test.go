@@ -0,0 +1,152 @@
+package plugin
+
+import "testing"
+
+// fakeMethodCodec is a minimal MethodCodec for exercising EventChannel's
+// listen/cancel demux without depending on the real wire format: it encodes
+// a MethodCall as its method name and nothing else.
+type fakeMethodCodec struct{}
+
+func (fakeMethodCodec) EncodeMethodCall(call MethodCall) ([]byte, error) {
+	return []byte(call.Method), nil
+}
+
+func (fakeMethodCodec) DecodeMethodCall(binaryMessage []byte) (MethodCall, error) {
+	return MethodCall{Method: string(binaryMessage)}, nil
+}
+
+func (fakeMethodCodec) EncodeSuccessEnvelope(reply interface{}) ([]byte, error) {
+	return []byte("success"), nil
+}
+
+func (fakeMethodCodec) EncodeErrorEnvelope(code string, message string, details interface{}) ([]byte, error) {
+	return []byte("error:" + code), nil
+}
+
+func (fakeMethodCodec) DecodeEnvelope(binaryReply []byte) (interface{}, error) {
+	return string(binaryReply), nil
+}
+
+// fakeMessenger is a minimal BinaryMessenger that records sent messages and
+// lets the test drive the registered channel handler directly.
+type fakeMessenger struct {
+	handler ChannelHandlerFunc
+	sent    [][]byte
+}
+
+func (m *fakeMessenger) Send(channel string, message []byte) ([]byte, error) {
+	m.sent = append(m.sent, message)
+	return nil, nil
+}
+
+func (m *fakeMessenger) SetChannelHandler(channel string, handler ChannelHandlerFunc) error {
+	m.handler = handler
+	return nil
+}
+
+var _ BinaryMessenger = &fakeMessenger{} // compile-time type check
+
+// fakeStreamHandler records OnListen/OnCancel invocations so tests can
+// assert on the subscription lifecycle EventChannel drives.
+type fakeStreamHandler struct {
+	listenCount int
+	lastSink    EventSink
+	cancelArgs  []interface{}
+}
+
+func (h *fakeStreamHandler) OnListen(arguments interface{}, sink EventSink) {
+	h.listenCount++
+	h.lastSink = sink
+}
+
+func (h *fakeStreamHandler) OnCancel(arguments interface{}) {
+	h.cancelArgs = append(h.cancelArgs, arguments)
+}
+
+var _ StreamHandler = &fakeStreamHandler{} // compile-time type check
+
+func TestEventChannelRelistenWithoutCancelRunsTeardown(t *testing.T) {
+	messenger := &fakeMessenger{}
+	channel := NewEventChannel(messenger, "test", fakeMethodCodec{})
+	handler := &fakeStreamHandler{}
+	channel.Handle(handler)
+
+	if _, err := messenger.handler([]byte("listen")); err != nil {
+		t.Fatalf("first listen: %v", err)
+	}
+	if handler.listenCount != 1 || len(handler.cancelArgs) != 0 {
+		t.Fatalf("after first listen: listenCount=%d cancelCalls=%d, want 1 and 0", handler.listenCount, len(handler.cancelArgs))
+	}
+	firstSink := handler.lastSink
+
+	// Flutter re-listening (e.g. a hot restart) without ever sending "cancel"
+	// must still run the previous subscription's teardown.
+	if _, err := messenger.handler([]byte("listen")); err != nil {
+		t.Fatalf("second listen: %v", err)
+	}
+	if handler.listenCount != 2 || len(handler.cancelArgs) != 1 {
+		t.Fatalf("after re-listen: listenCount=%d cancelCalls=%d, want 2 and 1", handler.listenCount, len(handler.cancelArgs))
+	}
+	if handler.cancelArgs[0] != nil {
+		t.Fatalf("teardown OnCancel arguments = %v, want nil", handler.cancelArgs[0])
+	}
+	if handler.lastSink == firstSink {
+		t.Fatalf("re-listen did not install a new sink")
+	}
+}
+
+func TestEventChannelCancelDoesNotDoubleTeardown(t *testing.T) {
+	messenger := &fakeMessenger{}
+	channel := NewEventChannel(messenger, "test", fakeMethodCodec{})
+	handler := &fakeStreamHandler{}
+	channel.Handle(handler)
+
+	if _, err := messenger.handler([]byte("listen")); err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	if _, err := messenger.handler([]byte("cancel")); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if len(handler.cancelArgs) != 1 {
+		t.Fatalf("cancelCalls = %d, want 1", len(handler.cancelArgs))
+	}
+
+	// A fresh listen after a real cancel must not trigger the re-listen
+	// teardown a second time, since there is no previous sink anymore.
+	if _, err := messenger.handler([]byte("listen")); err != nil {
+		t.Fatalf("second listen: %v", err)
+	}
+	if len(handler.cancelArgs) != 1 {
+		t.Fatalf("cancelCalls after fresh listen = %d, want 1", len(handler.cancelArgs))
+	}
+}
+
+func TestEventSinkEndOfStreamDeactivatesSink(t *testing.T) {
+	messenger := &fakeMessenger{}
+	channel := NewEventChannel(messenger, "test", fakeMethodCodec{})
+	handler := &fakeStreamHandler{}
+	channel.Handle(handler)
+
+	if _, err := messenger.handler([]byte("listen")); err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	sink := handler.lastSink
+
+	sink.Success("event")
+	sentAfterEvent := len(messenger.sent)
+	if sentAfterEvent == 0 {
+		t.Fatalf("Success before EndOfStream did not send anything")
+	}
+
+	sink.EndOfStream()
+	sentAfterEOS := len(messenger.sent)
+	if sentAfterEOS != sentAfterEvent+1 {
+		t.Fatalf("EndOfStream sent %d messages, want 1", sentAfterEOS-sentAfterEvent)
+	}
+
+	// The sink is now deactivated; further pushes on it must be no-ops.
+	sink.Success("late event")
+	if len(messenger.sent) != sentAfterEOS {
+		t.Fatalf("Success after EndOfStream sent a message, want no-op")
+	}
+}
@@ -0,0 +1,165 @@
+package flutter
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/go-flutter-desktop/go-flutter/plugin"
+	"github.com/pkg/errors"
+)
+
+const restorationChannelName = "flutter/restoration"
+
+// RestorationStore persists the opaque restoration data blob that the
+// Flutter framework hands to the engine via the flutter/restoration
+// channel. Embedders can provide their own implementation (e.g. encrypted
+// or in-memory) through OptionRestorationStore.
+type RestorationStore interface {
+	// Get returns the previously persisted restoration data, or nil if
+	// none was ever saved.
+	Get() ([]byte, error)
+
+	// Put persists the restoration data handed over by the framework.
+	Put(data []byte) error
+}
+
+// fileRestorationStore is the default RestorationStore, writing the
+// restoration data to a single file in the OS user-config directory, keyed
+// by application name.
+type fileRestorationStore struct {
+	path string
+}
+
+// newFileRestorationStore creates a RestorationStore backed by the file
+// restoration_state.bin in a directory named after applicationName inside
+// the OS user-config directory.
+func newFileRestorationStore(applicationName string) (*fileRestorationStore, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve user config dir for restoration store")
+	}
+	dir := filepath.Join(configDir, applicationName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrap(err, "failed to create restoration store directory")
+	}
+	return &fileRestorationStore{
+		path: filepath.Join(dir, "restoration_state.bin"),
+	}, nil
+}
+
+func (s *fileRestorationStore) Get() ([]byte, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read restoration state file")
+	}
+	return data, nil
+}
+
+func (s *fileRestorationStore) Put(data []byte) error {
+	if err := ioutil.WriteFile(s.path, data, 0644); err != nil {
+		return errors.Wrap(err, "failed to write restoration state file")
+	}
+	return nil
+}
+
+// restorationPlugin implements flutter.Plugin and handles method calls to
+// the flutter/restoration channel, used by the framework for state
+// restoration.
+type restorationPlugin struct {
+	messenger plugin.BinaryMessenger
+	channel   *plugin.MethodChannel
+
+	enabled bool
+	store   RestorationStore
+	config  *config
+}
+
+// defaultRestorationPlugin is registered by default in NewApplication.
+var defaultRestorationPlugin = &restorationPlugin{}
+
+var _ Plugin = &restorationPlugin{} // compile-time type check
+
+// OptionRestorationEnabled controls whether the engine is told that state
+// restoration data is available. When disabled, the "get" method on
+// flutter/restoration always replies with enabled: false and no data is
+// persisted.
+func OptionRestorationEnabled(enabled bool) Option {
+	return func(c *config) {
+		c.restorationEnabled = enabled
+		defaultRestorationPlugin.enabled = enabled
+		defaultRestorationPlugin.config = c
+	}
+}
+
+// OptionRestorationStore overrides the default file-backed RestorationStore,
+// allowing embedders to plug in an encrypted or in-memory store.
+func OptionRestorationStore(store RestorationStore) Option {
+	return func(c *config) {
+		c.restorationStore = store
+		defaultRestorationPlugin.store = store
+		defaultRestorationPlugin.config = c
+	}
+}
+
+func (p *restorationPlugin) InitPlugin(messenger plugin.BinaryMessenger) error {
+	p.messenger = messenger
+
+	// No store was supplied through OptionRestorationStore; fall back to the
+	// file-backed default, keyed by the configured application name. The
+	// name is read from config here, at Init time, rather than snapshotted
+	// when the restoration Options were evaluated, since Options run in
+	// caller order and OptionApplicationName isn't guaranteed to run first.
+	if p.enabled && p.store == nil {
+		var applicationName string
+		if p.config != nil {
+			applicationName = p.config.applicationName
+		}
+		store, err := newFileRestorationStore(applicationName)
+		if err != nil {
+			return errors.Wrap(err, "failed to create default restoration store")
+		}
+		p.store = store
+	}
+
+	// The engine's flutter/restoration channel carries the restoration blob
+	// as a raw binary buffer (Uint8List on the Dart side), which only the
+	// standard codec round-trips correctly; JSONMethodCodec would render it
+	// as a base64 string neither side expects.
+	p.channel = plugin.NewMethodChannel(p.messenger, restorationChannelName, plugin.StandardMethodCodec{})
+	p.channel.HandleFuncSync("get", p.handleGet)
+	p.channel.HandleFuncSync("put", p.handlePut)
+
+	return nil
+}
+
+func (p *restorationPlugin) handleGet(arguments interface{}) (reply interface{}, err error) {
+	if !p.enabled || p.store == nil {
+		return map[interface{}]interface{}{"enabled": p.enabled}, nil
+	}
+
+	data, err := p.store.Get()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load restoration data")
+	}
+	return map[interface{}]interface{}{"enabled": true, "data": data}, nil
+}
+
+func (p *restorationPlugin) handlePut(arguments interface{}) (reply interface{}, err error) {
+	if !p.enabled || p.store == nil {
+		return nil, nil
+	}
+
+	data, ok := arguments.([]byte)
+	if !ok {
+		return nil, errors.New("expected binary restoration data for handlePut")
+	}
+
+	if err := p.store.Put(data); err != nil {
+		return nil, errors.Wrap(err, "failed to persist restoration data")
+	}
+	return nil, nil
+}
@@ -11,9 +11,11 @@ import (
 
 const textinputChannelName = "flutter/textinput"
 
-// textinputPlugin implements flutter.Plugin and handles method calls to the
-// flutter/textinput channel.
-type textinputPlugin struct {
+// TextInputPlugin implements flutter.Plugin and handles method calls to the
+// flutter/textinput channel. It is exported so third-party IME/composition
+// plugins (e.g. CJK candidate windows, dictation) can drive text editing
+// without touching go-flutter internals.
+type TextInputPlugin struct {
 	messenger plugin.BinaryMessenger
 	window    *glfw.Window
 	channel   *plugin.MethodChannel
@@ -24,11 +26,174 @@ type textinputPlugin struct {
 	wordTravellerKey      glfw.ModifierKey
 	wordTravellerKeyShift glfw.ModifierKey
 
+	virtualKeyboardHandler VirtualKeyboardHandler
+	editingStateListener   func(clientID float64, conf ClientConfig)
+
 	clientID        float64
 	clientConf      argSetClientConf
 	word            []rune
 	selectionBase   int
 	selectionExtent int
+
+	// composingBase/composingExtent describe the IME preedit region
+	// currently being composed within word, using the -1/-1 "empty range"
+	// convention. They are set by SetComposingText (called from
+	// glfwPreeditCallback on GLFW forks with native IME support) and by
+	// handleSetEditingState from the state Flutter pushes; vanilla GLFW 3.2
+	// resolves dead keys itself before glfwCharCallback fires, so there is no
+	// preedit stage for this package to buffer.
+	composingBase   int
+	composingExtent int
+}
+
+// ClientConfig is the configuration Flutter sent for the currently selected
+// text input client via TextInput.setClient. It is an alias of the internal
+// argSetClientConf so callers outside this package don't need to depend on
+// unexported types.
+type ClientConfig = argSetClientConf
+
+// SetEditingStateListener registers a callback that fires whenever Flutter
+// pushes new editing state via TextInput.setEditingState, so third-party IME
+// plugins can observe framework-driven edits (e.g. autocorrect, undo).
+func (p *TextInputPlugin) SetEditingStateListener(listener func(clientID float64, conf ClientConfig)) {
+	p.editingStateListener = listener
+}
+
+// Client returns the currently selected text input client, if any. ok is
+// false when no client is selected (e.g. after TextInput.clearClient).
+func (p *TextInputPlugin) Client() (id float64, conf ClientConfig, ok bool) {
+	if p.clientID == 0 {
+		return 0, ClientConfig{}, false
+	}
+	return p.clientID, p.clientConf, true
+}
+
+// UpdateEditingState notifies Flutter that the editing state of the given
+// client changed, e.g. because a third-party IME committed text or moved the
+// selection outside of the normal key/char callbacks.
+func (p *TextInputPlugin) UpdateEditingState(clientID float64, text string, base, extent int) error {
+	return p.invoke("TextInputClient.updateEditingState", []interface{}{
+		clientID,
+		argsEditingState{
+			Text:            text,
+			SelectionBase:   base,
+			SelectionExtent: extent,
+			ComposingBase:   p.composingBase,
+			ComposingExtent: p.composingExtent,
+		},
+	})
+}
+
+// SetComposingText replaces the current IME composing region (or, if none is
+// active, the current selection) with text and marks it as composing, so
+// Flutter renders it as preedit/candidate text. cursor positions the caret
+// as an offset into text. Third-party IME plugins call this while the user
+// is still picking candidates, before the input is finalized.
+func (p *TextInputPlugin) SetComposingText(text string, cursor int) {
+	start := p.composingRegionStart()
+	end := p.composingRegionEnd()
+	if start > end {
+		// Flutter sends backward selections (selectionBase > selectionExtent).
+		start, end = end, start
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end > len(p.word) {
+		end = len(p.word)
+	}
+	if start > end {
+		start = end
+	}
+	runes := []rune(text)
+	if cursor < 0 {
+		cursor = 0
+	} else if cursor > len(runes) {
+		cursor = len(runes)
+	}
+
+	p.word = append(append(append([]rune{}, p.word[:start]...), runes...), p.word[end:]...)
+	p.composingBase = start
+	p.composingExtent = start + len(runes)
+	p.selectionBase = start + cursor
+	p.selectionExtent = p.selectionBase
+
+	p.sendEditingState()
+}
+
+// CommitComposingText finalizes the current composing text as regular input
+// and clears the composing region, leaving the already-inserted text in
+// place.
+func (p *TextInputPlugin) CommitComposingText() {
+	p.composingBase = -1
+	p.composingExtent = -1
+
+	p.sendEditingState()
+}
+
+// sendEditingState reports the current text, selection, and composing
+// region to Flutter. SetComposingText and CommitComposingText route through
+// this, rather than the plain updateEditingState used after ordinary
+// keystrokes, so the composing offsets they set actually reach
+// TextInputClient.updateEditingState.
+func (p *TextInputPlugin) sendEditingState() {
+	if err := p.UpdateEditingState(p.clientID, string(p.word), p.selectionBase, p.selectionExtent); err != nil {
+		fmt.Printf("go-flutter: failed to update editing state: %v\n", err)
+	}
+}
+
+func (p *TextInputPlugin) composingRegionStart() int {
+	if p.composingBase < 0 {
+		return p.selectionBase
+	}
+	return p.composingBase
+}
+
+func (p *TextInputPlugin) composingRegionEnd() int {
+	if p.composingExtent < 0 {
+		return p.selectionExtent
+	}
+	return p.composingExtent
+}
+
+// PerformAction notifies Flutter that the given TextInputAction (e.g.
+// "TextInputAction.done") was performed on the currently selected client.
+func (p *TextInputPlugin) PerformAction(action string) error {
+	return p.invoke("TextInputClient.performAction", []interface{}{p.clientID, action})
+}
+
+// invoke is the single choke point for outgoing TextInputClient.* calls,
+// mirroring the upstream GLFW text_input_plugin's outgoing message contract.
+func (p *TextInputPlugin) invoke(method string, arguments interface{}) error {
+	_, err := p.channel.InvokeMethod(method, arguments)
+	if err != nil {
+		return errors.Wrap(err, "failed to invoke "+method)
+	}
+	return nil
+}
+
+// VirtualKeyboardHandler is implemented by embedders that want to bring up
+// their own on-screen keyboard for touch-screen or IME-less environments.
+// It is invoked whenever the Flutter framework asks the engine to show or
+// hide the text input (e.g. when a TextField gains or loses focus).
+type VirtualKeyboardHandler interface {
+	// Show is called when Flutter requests the on-screen keyboard to be
+	// displayed for the given client configuration.
+	Show(clientConf argSetClientConf)
+
+	// Hide is called when Flutter requests the on-screen keyboard to be
+	// dismissed.
+	Hide()
+}
+
+// OptionVirtualKeyboardHandler registers a VirtualKeyboardHandler that is
+// notified on TextInput.show/TextInput.hide so embedders without a physical
+// keyboard can drive their own on-screen keyboard.
+func OptionVirtualKeyboardHandler(handler VirtualKeyboardHandler) Option {
+	return func(c *config) {
+		c.virtualKeyboardHandler = handler
+		defaultTextinputPlugin.virtualKeyboardHandler = handler
+	}
 }
 
 // keyboardShortcutsGLFW handle glfw.ModifierKey from glfwKeyCallback.
@@ -37,28 +202,64 @@ type keyboardShortcutsGLFW struct {
 }
 
 // all hardcoded because theres not pluggable renderer system.
-var defaultTextinputPlugin = &textinputPlugin{}
+var defaultTextinputPlugin = &TextInputPlugin{}
 
-var _ Plugin = &textinputPlugin{}     // compile-time type check
-var _ PluginGLFW = &textinputPlugin{} // compile-time type check
+var _ Plugin = &TextInputPlugin{}     // compile-time type check
+var _ PluginGLFW = &TextInputPlugin{} // compile-time type check
 
-func (p *textinputPlugin) InitPlugin(messenger plugin.BinaryMessenger) error {
+func (p *TextInputPlugin) InitPlugin(messenger plugin.BinaryMessenger) error {
 	p.messenger = messenger
+	p.composingBase = -1
+	p.composingExtent = -1
 
 	return nil
 }
 
-func (p *textinputPlugin) InitPluginGLFW(window *glfw.Window) error {
+func (p *TextInputPlugin) InitPluginGLFW(window *glfw.Window) error {
 	p.window = window
+	// Shares defaultKeyboardLayoutPlugin's detector so the SelectAll/Copy/
+	// Cut/Paste shortcuts below resolve against whatever layout was detected
+	// (or forced through OptionKeyboardLayout).
+	p.keyboardLayout = defaultKeyboardLayoutPlugin.detector.DetectLayout()
 	p.channel = plugin.NewMethodChannel(p.messenger, textinputChannelName, plugin.JSONMethodCodec{})
 	p.channel.HandleFuncSync("TextInput.setClient", p.handleSetClient)
 	p.channel.HandleFuncSync("TextInput.clearClient", p.handleClearClient)
 	p.channel.HandleFuncSync("TextInput.setEditingState", p.handleSetEditingState)
+	p.channel.HandleFuncSync("TextInput.show", p.handleShow)
+	p.channel.HandleFuncSync("TextInput.hide", p.handleHide)
+
+	// Some IME-enabled GLFW forks expose SetPreeditCallback, which reports
+	// composing text directly from the platform's input method. When it's
+	// available it is wired up here; otherwise no composing region is
+	// reported and characters arrive through glfwCharCallback only.
+	if preeditWindow, ok := interface{}(window).(preeditCallbackSetter); ok {
+		preeditWindow.SetPreeditCallback(p.glfwPreeditCallback)
+	}
 
 	return nil
 }
 
-func (p *textinputPlugin) handleSetClient(arguments interface{}) (reply interface{}, err error) {
+// preeditCallbackSetter is implemented by GLFW forks that surface native IME
+// preedit/composition events (not part of the upstream go-gl/glfw/v3.2 API).
+type preeditCallbackSetter interface {
+	SetPreeditCallback(cb func(preeditString string, cursorPos int))
+}
+
+// glfwPreeditCallback is wired up when the running GLFW build supports
+// native preedit events, forwarding IME candidate text straight into the
+// composing region.
+func (p *TextInputPlugin) glfwPreeditCallback(preeditString string, cursorPos int) {
+	if p.clientID == 0 {
+		return
+	}
+	if preeditString == "" {
+		p.CommitComposingText()
+		return
+	}
+	p.SetComposingText(preeditString, cursorPos)
+}
+
+func (p *TextInputPlugin) handleSetClient(arguments interface{}) (reply interface{}, err error) {
 	args := []json.RawMessage{}
 	err = json.Unmarshal(arguments.(json.RawMessage), &args)
 	if err != nil {
@@ -78,12 +279,12 @@ func (p *textinputPlugin) handleSetClient(arguments interface{}) (reply interfac
 	return nil, nil
 }
 
-func (p *textinputPlugin) handleClearClient(arguments interface{}) (reply interface{}, err error) {
+func (p *TextInputPlugin) handleClearClient(arguments interface{}) (reply interface{}, err error) {
 	p.clientID = 0
 	return nil, nil
 }
 
-func (p *textinputPlugin) handleSetEditingState(arguments interface{}) (reply interface{}, err error) {
+func (p *TextInputPlugin) handleSetEditingState(arguments interface{}) (reply interface{}, err error) {
 	if p.clientID == 0 {
 		return nil, errors.New("cannot set editing state when no client is selected")
 	}
@@ -97,19 +298,47 @@ func (p *textinputPlugin) handleSetEditingState(arguments interface{}) (reply in
 	p.word = []rune(editingState.Text)
 	p.selectionBase = editingState.SelectionBase
 	p.selectionExtent = editingState.SelectionExtent
+	p.composingBase = editingState.ComposingBase
+	p.composingExtent = editingState.ComposingExtent
+
+	if p.editingStateListener != nil {
+		p.editingStateListener(p.clientID, p.clientConf)
+	}
+	return nil, nil
+}
+
+func (p *TextInputPlugin) handleShow(arguments interface{}) (reply interface{}, err error) {
+	if p.virtualKeyboardHandler != nil {
+		p.virtualKeyboardHandler.Show(p.clientConf)
+	}
+	return nil, nil
+}
+
+func (p *TextInputPlugin) handleHide(arguments interface{}) (reply interface{}, err error) {
+	if p.virtualKeyboardHandler != nil {
+		p.virtualKeyboardHandler.Hide()
+	}
 	return nil, nil
 }
 
-func (p *textinputPlugin) glfwCharCallback(w *glfw.Window, char rune) {
+func (p *TextInputPlugin) glfwCharCallback(w *glfw.Window, char rune) {
 	if p.clientID == 0 {
 		return
 	}
+
+	// Vanilla GLFW 3.2 resolves dead keys (e.g. diacritics on AZERTY or
+	// international layouts) against the following keystroke itself and
+	// only then fires this callback with the combined character, so there
+	// is no intermediate preedit stage to buffer here. GLFW forks that do
+	// surface a native preedit/composing stage are handled separately
+	// through glfwPreeditCallback.
 	p.addChar([]rune{char})
 }
 
-func (p *textinputPlugin) glfwKeyCallback(window *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+func (p *TextInputPlugin) glfwKeyCallback(window *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
 
 	keyboardShortcutBind := keyboardShortcutsGLFW{mod: mods}
+
 	if key == glfw.KeyEscape && action == glfw.Press {
 		_, err := defaultNavigationPlugin.channel.InvokeMethod("popRoute", nil)
 		if err != nil {
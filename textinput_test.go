@@ -0,0 +1,159 @@
+package flutter
+
+import (
+	"testing"
+
+	"github.com/go-flutter-desktop/go-flutter/plugin"
+)
+
+// fakeBinaryMessenger is a minimal plugin.BinaryMessenger that discards
+// outgoing sends, letting SetComposingText/CommitComposingText run their
+// invoke path without a real Flutter engine on the other end.
+type fakeBinaryMessenger struct{}
+
+func (fakeBinaryMessenger) Send(channel string, message []byte) ([]byte, error) {
+	return nil, nil
+}
+
+func (fakeBinaryMessenger) SetChannelHandler(channel string, handler plugin.ChannelHandlerFunc) error {
+	return nil
+}
+
+// capturingMethodCodec records the last outgoing MethodCall so tests can
+// inspect the argsEditingState actually handed to
+// TextInputClient.updateEditingState, rather than just the plugin's internal
+// state.
+type capturingMethodCodec struct {
+	lastCall plugin.MethodCall
+}
+
+func (c *capturingMethodCodec) EncodeMethodCall(call plugin.MethodCall) ([]byte, error) {
+	c.lastCall = call
+	return nil, nil
+}
+
+func (c *capturingMethodCodec) DecodeMethodCall(binaryMessage []byte) (plugin.MethodCall, error) {
+	return plugin.MethodCall{}, nil
+}
+
+func (c *capturingMethodCodec) EncodeSuccessEnvelope(reply interface{}) ([]byte, error) {
+	return nil, nil
+}
+
+func (c *capturingMethodCodec) EncodeErrorEnvelope(code string, message string, details interface{}) ([]byte, error) {
+	return nil, nil
+}
+
+func (c *capturingMethodCodec) DecodeEnvelope(binaryReply []byte) (interface{}, error) {
+	return nil, nil
+}
+
+// newTestTextInputPlugin returns a TextInputPlugin wired to a capturing
+// fake channel, with a client already selected, ready to drive
+// SetComposingText/CommitComposingText directly.
+func newTestTextInputPlugin(word string, selectionBase, selectionExtent int) (*TextInputPlugin, *capturingMethodCodec) {
+	codec := &capturingMethodCodec{}
+	p := &TextInputPlugin{
+		clientID:        1,
+		word:            []rune(word),
+		selectionBase:   selectionBase,
+		selectionExtent: selectionExtent,
+		composingBase:   -1,
+		composingExtent: -1,
+	}
+	p.channel = plugin.NewMethodChannel(fakeBinaryMessenger{}, textinputChannelName, codec)
+	return p, codec
+}
+
+func (c *capturingMethodCodec) lastEditingState(t *testing.T) argsEditingState {
+	t.Helper()
+	args, ok := c.lastCall.Arguments.([]interface{})
+	if !ok || len(args) != 2 {
+		t.Fatalf("unexpected TextInputClient.updateEditingState arguments: %#v", c.lastCall.Arguments)
+	}
+	state, ok := args[1].(argsEditingState)
+	if !ok {
+		t.Fatalf("second argument is %T, want argsEditingState", args[1])
+	}
+	return state
+}
+
+func TestSetComposingTextReplacesSelectionAndPositionsCursor(t *testing.T) {
+	p, codec := newTestTextInputPlugin("hello", 1, 3)
+
+	p.SetComposingText("XY", 1)
+
+	if got := string(p.word); got != "hXYlo" {
+		t.Fatalf("word = %q, want %q", got, "hXYlo")
+	}
+	if p.composingBase != 1 || p.composingExtent != 3 {
+		t.Fatalf("composing region = [%d,%d), want [1,3)", p.composingBase, p.composingExtent)
+	}
+	if p.selectionBase != 2 || p.selectionExtent != 2 {
+		t.Fatalf("selection = [%d,%d], want [2,2]", p.selectionBase, p.selectionExtent)
+	}
+
+	state := codec.lastEditingState(t)
+	if state.ComposingBase != 1 || state.ComposingExtent != 3 {
+		t.Fatalf("sent composing region = [%d,%d), want [1,3)", state.ComposingBase, state.ComposingExtent)
+	}
+}
+
+func TestSetComposingTextClampsOutOfRangeCursor(t *testing.T) {
+	p, _ := newTestTextInputPlugin("hi", 0, 0)
+
+	p.SetComposingText("abc", 100)
+
+	if p.selectionBase != len("abc") || p.selectionExtent != len("abc") {
+		t.Fatalf("selection = [%d,%d], want caret clamped to end of composed text", p.selectionBase, p.selectionExtent)
+	}
+}
+
+func TestSetComposingTextClampsNegativeCursor(t *testing.T) {
+	p, _ := newTestTextInputPlugin("hi", 0, 0)
+
+	p.SetComposingText("abc", -5)
+
+	if p.selectionBase != 0 || p.selectionExtent != 0 {
+		t.Fatalf("selection = [%d,%d], want caret clamped to 0", p.selectionBase, p.selectionExtent)
+	}
+}
+
+func TestSetComposingTextNormalizesBackwardSelection(t *testing.T) {
+	p, _ := newTestTextInputPlugin("hello", 3, 1)
+
+	p.SetComposingText("Z", 1)
+
+	if got := string(p.word); got != "hZlo" {
+		t.Fatalf("word = %q, want %q", got, "hZlo")
+	}
+}
+
+func TestSetComposingTextClampsOutOfBoundsRegion(t *testing.T) {
+	p, _ := newTestTextInputPlugin("hi", -4, 40)
+
+	p.SetComposingText("ok", 0)
+
+	if got := string(p.word); got != "ok" {
+		t.Fatalf("word = %q, want %q", got, "ok")
+	}
+}
+
+func TestCommitComposingTextClearsComposingRegionOnly(t *testing.T) {
+	p, codec := newTestTextInputPlugin("hello", 1, 3)
+	p.SetComposingText("XY", 1)
+
+	p.CommitComposingText()
+
+	if p.composingBase != -1 || p.composingExtent != -1 {
+		t.Fatalf("composing region = [%d,%d), want cleared to [-1,-1)", p.composingBase, p.composingExtent)
+	}
+	if got := string(p.word); got != "hXYlo" {
+		t.Fatalf("word = %q, want unchanged %q", got, "hXYlo")
+	}
+
+	state := codec.lastEditingState(t)
+	if state.ComposingBase != -1 || state.ComposingExtent != -1 {
+		t.Fatalf("sent composing region = [%d,%d), want [-1,-1)", state.ComposingBase, state.ComposingExtent)
+	}
+}
@@ -0,0 +1,33 @@
+package flutter
+
+// argSetClientConf is the configuration object Flutter sends as the second
+// argument of TextInput.setClient.
+type argSetClientConf struct {
+	InputType struct {
+		Name string `json:"name"`
+	} `json:"inputType"`
+	ObscureText        bool   `json:"obscureText"`
+	AutoCorrect        bool   `json:"autocorrect"`
+	ActionLabel        string `json:"actionLabel"`
+	TextCapitalization string `json:"textCapitalization"`
+	KeyboardAppearance string `json:"keyboardAppearance"`
+	InputAction        string `json:"inputAction"`
+}
+
+// argsEditingState mirrors Flutter's TextEditingValue, exchanged both ways
+// on the flutter/textinput channel: Flutter pushes it via
+// TextInput.setEditingState, and go-flutter reports it back via
+// TextInputClient.updateEditingState.
+//
+// ComposingBase/ComposingExtent describe the IME preedit (composing) region
+// within Text, using the same -1/-1 "empty range" convention as Flutter's
+// TextRange.empty for text that isn't currently being composed.
+type argsEditingState struct {
+	Text                   string `json:"text"`
+	SelectionBase          int    `json:"selectionBase"`
+	SelectionExtent        int    `json:"selectionExtent"`
+	SelectionAffinity      string `json:"selectionAffinity"`
+	SelectionIsDirectional bool   `json:"selectionIsDirectional"`
+	ComposingBase          int    `json:"composingBase"`
+	ComposingExtent        int    `json:"composingExtent"`
+}